@@ -0,0 +1,99 @@
+package ghmcp
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// instrumentedToolHandler wraps handler so every call to tool (registered
+// under toolset) records a metrics.Registry.ToolCallDuration measurement
+// for the handler's own execution time, and, when an access logger is
+// installed, one log.AccessRecord via log.AccessLogMiddleware -- this is
+// the dispatch point real toolset registrars (the default one in
+// toolset_registrar.go, and any custom ToolsetRegistrar) route calls
+// through, rather than bolting observability onto each tool individually.
+//
+// RequestID, SessionID, and ActorLogin on the resulting AccessRecord are
+// left at their zero value: this minimal dispatch path has no
+// deps-injected session/identity context to read them from. A
+// ToolsetRegistrar built against the real tool surface (pkg/github) has
+// that context available and should populate them by building its own
+// log.ToolCall instead of going through accessLoggedCall.
+func instrumentedToolHandler(tool, toolset string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var handlerDuration time.Duration
+
+		result, err := accessLoggedCall(tool, toolset, req, func() (*mcp.CallToolResult, error) {
+			start := time.Now()
+			r, callErr := handler(ctx, req)
+			handlerDuration = time.Since(start)
+			return r, callErr
+		})
+
+		outcome := metrics.OutcomeOK
+		if err != nil || (result != nil && result.IsError) {
+			outcome = metrics.OutcomeError
+		}
+		currentMetrics().ToolCallDuration(tool, toolset, outcome, handlerDuration)
+
+		return result, err
+	}
+}
+
+// accessLoggedCall runs call through log.AccessLogMiddleware when an
+// AccessLogger is installed, translating between server.ToolHandlerFunc's
+// (*mcp.CallToolResult, error) return shape and the
+// (log.ToolCallResult, error) shape AccessLogMiddleware expects. It's a
+// plain passthrough to call when access logging is disabled.
+//
+// BytesIn/BytesOut are best-effort byte counts of req's arguments and
+// call's result, computed via JSON marshaling rather than a real wire
+// size, since this dispatch path sits above mcp-go's transport framing.
+func accessLoggedCall(tool, toolset string, req mcp.CallToolRequest, call func() (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	logger := AccessLogger()
+	if logger == nil {
+		return call()
+	}
+
+	var result *mcp.CallToolResult
+
+	middleware := log.AccessLogMiddleware(logger, slog.Default())
+	_, err := middleware(func(log.ToolCall) (log.ToolCallResult, error) {
+		r, callErr := call()
+		result = r
+
+		outcome := "ok"
+		bytesOut := 0
+		switch {
+		case callErr != nil:
+			outcome = "tool_error"
+		case r != nil && r.IsError:
+			outcome = "tool_error"
+		}
+		if r != nil {
+			if encoded, marshalErr := json.Marshal(r); marshalErr == nil {
+				bytesOut = len(encoded)
+			}
+		}
+		return log.ToolCallResult{Outcome: outcome, BytesOut: bytesOut}, callErr
+	})(log.ToolCall{Tool: tool, Toolset: toolset, BytesIn: marshaledLen(req.Params.Arguments)})
+
+	return result, err
+}
+
+// marshaledLen returns the length of v's JSON encoding, or 0 if v can't be
+// marshaled.
+func marshaledLen(v any) int {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
@@ -0,0 +1,91 @@
+package ghmcp
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spyRegistry records ToolCallDuration calls for assertions; every other
+// method is a no-op.
+type spyRegistry struct {
+	metrics.NoopRegistry
+	calls []string
+}
+
+func (s *spyRegistry) ToolCallDuration(tool, toolset string, outcome metrics.Outcome, _ time.Duration) {
+	s.calls = append(s.calls, tool+"/"+toolset+"/"+string(outcome))
+}
+
+func TestInstrumentedToolHandler_RecordsDuration(t *testing.T) {
+	// Not t.Parallel(): mutates the package-level activeMetrics singleton.
+	defer SetMetricsRegistry(nil)
+
+	spy := &spyRegistry{}
+	SetMetricsRegistry(spy)
+
+	handler := instrumentedToolHandler("get_me", "context", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, spy.calls, 1)
+	assert.Equal(t, "get_me/context/ok", spy.calls[0])
+}
+
+func TestInstrumentedToolHandler_RecordsErrorOutcome(t *testing.T) {
+	defer SetMetricsRegistry(nil)
+
+	spy := &spyRegistry{}
+	SetMetricsRegistry(spy)
+
+	handler := instrumentedToolHandler("get_me", "context", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("boom"), nil
+	})
+
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, spy.calls, 1)
+	assert.Equal(t, "get_me/context/tool_error", spy.calls[0])
+}
+
+func TestInstrumentedToolHandler_WritesOneAccessRecordPerCall(t *testing.T) {
+	// Not t.Parallel(): mutates the package-level accessLogger singleton.
+	defer setAccessLogger(nil)
+
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	setAccessLogger(log.NewAccessLogger(f, log.AccessLogFormatJSON, nil))
+
+	handler := instrumentedToolHandler("get_me", "context", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	_, err = handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	_, err = handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	require.Len(t, lines, 2, "expected exactly one AccessRecord per call")
+	for _, line := range lines {
+		assert.Contains(t, string(line), `"tool":"get_me"`)
+		assert.NotContains(t, string(line), `"bytes_out":0`, "expected the marshaled result to contribute a non-zero byte count")
+	}
+}
@@ -0,0 +1,32 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/github/github-mcp-server/pkg/metrics"
+)
+
+// NewMetricsRegistry builds the metrics.Registry described by cfg's
+// MetricsAddr/MetricsBackend, returning metrics.NoopRegistry{} when
+// MetricsAddr is empty. Callers should run it alongside NewMCPServer and
+// invoke Registry.Shutdown during the server's own graceful shutdown.
+func NewMetricsRegistry(ctx context.Context, cfg MCPServerConfig) (metrics.Registry, error) {
+	if cfg.MetricsAddr == "" {
+		return metrics.NoopRegistry{}, nil
+	}
+
+	backend := cfg.MetricsBackend
+	if backend == "" {
+		backend = MetricsBackendPrometheus
+	}
+
+	switch backend {
+	case MetricsBackendPrometheus:
+		return metrics.NewPrometheusRegistry(cfg.MetricsAddr)
+	case MetricsBackendOTLP:
+		return metrics.NewOTLPRegistry(ctx, cfg.MetricsAddr)
+	default:
+		return nil, fmt.Errorf("ghmcp: unknown metrics backend %q", backend)
+	}
+}
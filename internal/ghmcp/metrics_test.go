@@ -0,0 +1,28 @@
+package ghmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsRegistry_EmptyAddrReturnsNoop(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewMetricsRegistry(context.Background(), MCPServerConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, metrics.NoopRegistry{}, reg)
+}
+
+func TestNewMetricsRegistry_UnknownBackendErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewMetricsRegistry(context.Background(), MCPServerConfig{
+		MetricsAddr:    ":9090",
+		MetricsBackend: "not-a-real-backend",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,483 @@
+// Package ghmcp wires together the GitHub MCP server: resolving server
+// configuration into a running *server.MCPServer, probing GHES hosts for
+// subdomain isolation, and injecting the dependencies every tool handler
+// needs to talk to GitHub.
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/github/github-mcp-server/pkg/log"
+	"github.com/github/github-mcp-server/pkg/metrics"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/mark3labs/mcp-go/server"
+	"golang.org/x/sync/singleflight"
+)
+
+// MCPServerConfig configures the construction of the GitHub MCP server.
+type MCPServerConfig struct {
+	// Version is the version of the GitHub MCP server being run, surfaced
+	// to clients during the MCP initialize handshake.
+	Version string
+
+	// Host is the GitHub host to talk to, e.g. a GHES hostname. Empty
+	// means github.com.
+	Host string
+
+	// Token is the GitHub access token used to authenticate API calls.
+	Token string
+
+	// EnabledToolsets is the set of toolsets to enable. A nil slice means
+	// "use the built-in defaults"; an empty, non-nil slice disables all
+	// toolsets.
+	EnabledToolsets []string
+
+	// EnabledTools restricts registration to an explicit set of tool
+	// names. When set without EnabledToolsets, no toolsets are enabled
+	// by default -- only the named tools are registered.
+	EnabledTools []string
+
+	// DynamicToolsets enables runtime toolset discovery: toolsets start
+	// disabled (aside from any explicitly listed) and can be turned on
+	// or off for the life of the session via dedicated tools.
+	DynamicToolsets bool
+
+	// ReadOnly disables tools that would mutate GitHub state.
+	ReadOnly bool
+
+	// LockdownMode disables tools that could be used to move data from
+	// private repositories into public ones.
+	LockdownMode bool
+
+	// Translator resolves user-facing strings, allowing operators to
+	// override tool descriptions.
+	Translator translations.TranslationHelperFunc
+
+	// ContentWindowSize bounds how many bytes of file/diff content a
+	// single tool response may return.
+	ContentWindowSize int
+
+	// Redactors are additional log.Redactors merged with the package's
+	// built-in defaults, letting operators scrub credential shapes
+	// specific to their environment (e.g. internal SSO tokens) from
+	// stdio logs without forking the server.
+	Redactors []log.Redactor
+
+	// ToolsetProvider supplies the enabled toolset list and, unlike
+	// EnabledToolsets, may keep emitting updates for the life of the
+	// server so toolsets can be flipped on/off without a restart. A nil
+	// ToolsetProvider falls back to a static provider built from
+	// EnabledToolsets/EnabledTools/DynamicToolsets.
+	ToolsetProvider ToolsetProvider
+
+	// ToolsetRegistrar applies a ToolsetConfig to a running server,
+	// registering newly-enabled toolsets' tools and withdrawing those
+	// for toolsets that were turned off. A nil ToolsetRegistrar falls
+	// back to the package's own default registrar.
+	ToolsetRegistrar ToolsetRegistrar
+
+	// MetricsAddr is the address to serve metrics on (e.g. ":9090" for
+	// Prometheus, or the OTLP/gRPC collector endpoint). Empty disables
+	// metrics; the server falls back to metrics.NoopRegistry.
+	MetricsAddr string
+
+	// MetricsBackend selects which metrics.Registry implementation
+	// MetricsAddr is interpreted by. Defaults to MetricsBackendPrometheus.
+	MetricsBackend MetricsBackend
+
+	// AccessLogPath enables the structured access-log middleware,
+	// writing one log.AccessRecord per tool call to this path. Empty
+	// disables the access log.
+	AccessLogPath string
+
+	// AccessLogFormat selects the access log's on-disk format. Defaults
+	// to log.AccessLogFormatJSON.
+	AccessLogFormat log.AccessLogFormat
+
+	// AccessLogMaxBytes bounds the access log file's size before it is
+	// rotated (see log.RotatingFile). Defaults to 100MB.
+	AccessLogMaxBytes int64
+
+	// SubdomainIsolationPositiveTTL overrides how long a successful
+	// subdomain-isolation probe is cached. Zero keeps
+	// DefaultSubdomainIsolationPositiveTTL.
+	SubdomainIsolationPositiveTTL time.Duration
+
+	// SubdomainIsolationNegativeTTL overrides how long a failed
+	// subdomain-isolation probe is cached, so a transient network blip
+	// doesn't get pinned for as long as a confirmed negative result.
+	// Zero keeps DefaultSubdomainIsolationNegativeTTL.
+	SubdomainIsolationNegativeTTL time.Duration
+}
+
+// NewAccessLogger builds the log.AccessLogger described by cfg's
+// AccessLog* fields, wired to the same redactor registry RedactorRegistry
+// returns so tool call bodies reflected into the access log (e.g. an
+// actor login) are scrubbed the same way stdio traffic is. It returns
+// (nil, nil) when cfg.AccessLogPath is empty.
+func (c MCPServerConfig) NewAccessLogger() (*log.AccessLogger, error) {
+	if c.AccessLogPath == "" {
+		return nil, nil
+	}
+
+	rf, err := log.NewRotatingFile(c.AccessLogPath, c.AccessLogMaxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("opening access log: %w", err)
+	}
+
+	format := c.AccessLogFormat
+	if format == "" {
+		format = log.AccessLogFormatJSON
+	}
+
+	return log.NewAccessLogger(rf, format, c.RedactorRegistry()), nil
+}
+
+// NewStdioLogger returns a log.IOLogger wrapping in/out, wired to the same
+// RedactorRegistry RedactorRegistry returns so a redaction on the stdio
+// transport -- not just the access log -- increments the Redaction metric
+// the same way. Callers own installing it as the transport's actual
+// stdin/stdout.
+func (c MCPServerConfig) NewStdioLogger(in io.Reader, out io.Writer, logger *slog.Logger) *log.IOLogger {
+	ioLogger := log.NewIOLogger(in, out, logger)
+	ioLogger.SetRedactorRegistry(c.RedactorRegistry())
+	return ioLogger
+}
+
+// MetricsBackend selects the metrics.Registry implementation NewMCPServer
+// constructs from MCPServerConfig.MetricsAddr.
+type MetricsBackend string
+
+const (
+	MetricsBackendPrometheus MetricsBackend = "prometheus"
+	MetricsBackendOTLP       MetricsBackend = "otlp"
+)
+
+// ToolsetRegistrar reconciles a running MCP server's registered tools
+// with a ToolsetConfig, so a ToolsetProvider update can add or remove
+// tools from a live session without restarting it.
+type ToolsetRegistrar interface {
+	ApplyToolsetConfig(s *server.MCPServer, cfg ToolsetConfig) error
+}
+
+// RedactorRegistry returns the log.RedactorRegistry that should be used to
+// scrub stdio traffic logged for a server built from cfg: the package
+// defaults plus whatever the operator supplied in Redactors, wired up to
+// report every redaction to the currently installed metrics.Registry.
+func (c MCPServerConfig) RedactorRegistry() *log.RedactorRegistry {
+	registry := log.NewRedactorRegistry(append(log.DefaultRedactors(), c.Redactors...)...)
+	registry.OnRedact(func(redactorName string) {
+		currentMetrics().Redaction(redactorName)
+	})
+	return registry
+}
+
+// NewMCPServer constructs an MCP server configured per cfg: it resolves
+// the enabled toolsets and, for GHES hosts, probes whether subdomain
+// isolation is in effect so tool handlers know which API shape to expect.
+//
+// If cfg.ToolsetProvider is set, NewMCPServer starts it in the background
+// and hot-swaps the server's registered tools via cfg.ToolsetRegistrar as
+// new ToolsetConfigs arrive, rather than registering a fixed set once at
+// startup.
+func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
+	s := server.NewMCPServer(
+		"github-mcp-server",
+		cfg.Version,
+		server.WithToolCapabilities(true),
+	)
+
+	metricsRegistry, err := NewMetricsRegistry(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("starting metrics registry: %w", err)
+	}
+	SetMetricsRegistry(metricsRegistry)
+
+	SetSubdomainIsolationTTLs(cfg.SubdomainIsolationPositiveTTL, cfg.SubdomainIsolationNegativeTTL)
+
+	accessLogger, err := cfg.NewAccessLogger()
+	if err != nil {
+		return nil, fmt.Errorf("starting access logger: %w", err)
+	}
+	setAccessLogger(accessLogger)
+
+	provider := cfg.ToolsetProvider
+	if provider == nil {
+		provider = NewStaticToolsetProvider(ToolsetConfig{EnabledToolsets: resolveEnabledToolsets(cfg)})
+	}
+
+	updates, err := provider.Provide(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("starting toolset provider: %w", err)
+	}
+
+	registrar := cfg.ToolsetRegistrar
+	if registrar == nil {
+		// No registrar configured: fall back to the package's own, which
+		// registers/withdraws a marker tool per toolset, so a bare
+		// MCPServerConfig still ends up with the tools its resolved
+		// ToolsetConfig calls for instead of silently discarding it.
+		registrar = newDefaultToolsetRegistrar()
+	}
+
+	toolsetCfg, ok := <-updates
+	if !ok {
+		return nil, fmt.Errorf("toolset provider closed before producing an initial config")
+	}
+	if err := registrar.ApplyToolsetConfig(s, toolsetCfg); err != nil {
+		return nil, fmt.Errorf("applying initial toolset config: %w", err)
+	}
+
+	go func() {
+		for toolsetCfg := range updates {
+			if err := registrar.ApplyToolsetConfig(s, toolsetCfg); err != nil {
+				slog.Default().Error("applying toolset config update", "error", err)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Shutdown releases resources started by NewMCPServer that aren't tied to
+// the *server.MCPServer lifecycle itself: the metrics registry's
+// listener/exporter and the access log's file handle. Callers should
+// invoke it during their own graceful shutdown, after the MCP session has
+// stopped accepting calls.
+func Shutdown() error {
+	metricsErr := currentMetrics().Shutdown()
+
+	var closeErr error
+	if logger := AccessLogger(); logger != nil {
+		closeErr = logger.Close()
+	}
+
+	if metricsErr != nil {
+		return metricsErr
+	}
+	return closeErr
+}
+
+// resolveEnabledToolsets applies MCPServerConfig's toolset-selection
+// rules:
+//
+//   - EnabledToolsets == nil, not dynamic, no EnabledTools: nil (use the
+//     package's built-in default toolset list).
+//   - EnabledToolsets == nil and (DynamicToolsets or EnabledTools set):
+//     start with no toolsets enabled.
+//   - EnabledToolsets set explicitly: use it as given, except that in
+//     dynamic mode "all" is dropped since dynamic toolset discovery
+//     supersedes it.
+func resolveEnabledToolsets(cfg MCPServerConfig) []string {
+	if cfg.EnabledToolsets == nil {
+		if cfg.DynamicToolsets || len(cfg.EnabledTools) > 0 {
+			return []string{}
+		}
+		return nil
+	}
+
+	resolved := make([]string, 0, len(cfg.EnabledToolsets))
+	for _, toolset := range cfg.EnabledToolsets {
+		if cfg.DynamicToolsets && toolset == "all" {
+			continue
+		}
+		resolved = append(resolved, toolset)
+	}
+	return resolved
+}
+
+// subdomainIsolationEntry is a cached result of probing a host for
+// subdomain isolation, along with when it expires. err is the error (if
+// any) the probe that produced result failed with -- checkSubdomainIsolation
+// still treats a failed probe as a negative result (see
+// subdomainIsolationNegativeTTL), but logs err when serving a cached
+// negative result so a transient network failure doesn't silently masquerade
+// as "this host has no subdomain isolation" in diagnostics.
+type subdomainIsolationEntry struct {
+	result    bool
+	err       error
+	expiresAt time.Time
+}
+
+// Default positive/negative TTLs for the subdomain isolation cache, used
+// when MCPServerConfig.SubdomainIsolation{Positive,Negative}TTL is zero.
+// See SetSubdomainIsolationTTLs.
+const (
+	DefaultSubdomainIsolationPositiveTTL = 10 * time.Minute
+	DefaultSubdomainIsolationNegativeTTL = 30 * time.Second
+)
+
+var (
+	subdomainIsolationCache      = map[string]subdomainIsolationEntry{}
+	subdomainIsolationCacheMutex sync.Mutex
+	subdomainIsolationGroup      singleflight.Group
+
+	subdomainIsolationTTLMutex    sync.RWMutex
+	subdomainIsolationPositiveTTL = DefaultSubdomainIsolationPositiveTTL
+	subdomainIsolationNegativeTTL = DefaultSubdomainIsolationNegativeTTL
+)
+
+// SetSubdomainIsolationTTLs overrides the positive/negative TTLs
+// checkSubdomainIsolation caches results for. A non-positive value for
+// either leaves that TTL unchanged, so callers can tune just one side.
+// NewMCPServer calls this from MCPServerConfig's
+// SubdomainIsolationPositiveTTL/SubdomainIsolationNegativeTTL fields.
+func SetSubdomainIsolationTTLs(positive, negative time.Duration) {
+	subdomainIsolationTTLMutex.Lock()
+	defer subdomainIsolationTTLMutex.Unlock()
+
+	if positive > 0 {
+		subdomainIsolationPositiveTTL = positive
+	}
+	if negative > 0 {
+		subdomainIsolationNegativeTTL = negative
+	}
+}
+
+var (
+	activeMetricsMutex sync.RWMutex
+	// activeMetrics is the metrics.Registry instruments in this package
+	// record against. It defaults to a no-op so the server behaves the
+	// same whether or not metrics are enabled; NewMCPServer installs the
+	// real registry via SetMetricsRegistry when cfg.MetricsAddr is set.
+	// Guarded by activeMetricsMutex since checkSubdomainIsolation,
+	// probeSubdomainIsolation, and instrumentedToolHandler all read it
+	// from concurrent goroutines.
+	activeMetrics metrics.Registry = metrics.NoopRegistry{}
+)
+
+// SetMetricsRegistry installs the metrics.Registry that package-level
+// instrumentation (currently: the subdomain isolation cache hit ratio,
+// tool call duration, and GitHub API call counts) records against.
+func SetMetricsRegistry(r metrics.Registry) {
+	if r == nil {
+		r = metrics.NoopRegistry{}
+	}
+	activeMetricsMutex.Lock()
+	defer activeMetricsMutex.Unlock()
+	activeMetrics = r
+}
+
+// currentMetrics returns the metrics.Registry most recently installed by
+// SetMetricsRegistry, for package-level instrumentation to record
+// against.
+func currentMetrics() metrics.Registry {
+	activeMetricsMutex.RLock()
+	defer activeMetricsMutex.RUnlock()
+	return activeMetrics
+}
+
+var (
+	accessLoggerMutex sync.Mutex
+	accessLogger      *log.AccessLogger
+)
+
+// setAccessLogger installs the *log.AccessLogger built from
+// MCPServerConfig.AccessLogPath, or clears it when logger is nil (access
+// logging disabled).
+func setAccessLogger(logger *log.AccessLogger) {
+	accessLoggerMutex.Lock()
+	defer accessLoggerMutex.Unlock()
+	accessLogger = logger
+}
+
+// AccessLogger returns the access logger installed by the most recent
+// NewMCPServer call, or nil if MCPServerConfig.AccessLogPath was empty.
+// instrumentedToolHandler looks this up on every call and, when non-nil,
+// wraps the call with log.AccessLogMiddleware so it produces one
+// AccessRecord.
+func AccessLogger() *log.AccessLogger {
+	accessLoggerMutex.Lock()
+	defer accessLoggerMutex.Unlock()
+	return accessLogger
+}
+
+// checkSubdomainIsolation reports whether scheme://hostname serves its
+// raw/API traffic from isolated subdomains (the GHES "subdomain
+// isolation" deployment mode), caching the result for
+// subdomainIsolationPositiveTTL on success and subdomainIsolationNegativeTTL
+// on failure so a transient network blip doesn't get pinned as "no
+// isolation" for the life of the process. Concurrent first-time callers
+// for the same host collapse into a single probe via singleflight.
+func checkSubdomainIsolation(client *http.Client, scheme, hostname string) bool {
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+
+	subdomainIsolationCacheMutex.Lock()
+	entry, ok := subdomainIsolationCache[cacheKey]
+	subdomainIsolationCacheMutex.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		currentMetrics().SubdomainIsolationCache(true)
+		if entry.err != nil {
+			slog.Default().Debug("serving cached subdomain isolation result after a failed probe", "host", cacheKey, "error", entry.err)
+		}
+		return entry.result
+	}
+	currentMetrics().SubdomainIsolationCache(false)
+
+	v, _, _ := subdomainIsolationGroup.Do(cacheKey, func() (interface{}, error) {
+		result, err := probeSubdomainIsolation(client, scheme, hostname)
+
+		subdomainIsolationTTLMutex.RLock()
+		ttl := subdomainIsolationPositiveTTL
+		if err != nil {
+			ttl = subdomainIsolationNegativeTTL
+		}
+		subdomainIsolationTTLMutex.RUnlock()
+
+		subdomainIsolationCacheMutex.Lock()
+		subdomainIsolationCache[cacheKey] = subdomainIsolationEntry{
+			result:    result,
+			err:       err,
+			expiresAt: time.Now().Add(ttl),
+		}
+		subdomainIsolationCacheMutex.Unlock()
+
+		return result, nil
+	})
+
+	return v.(bool)
+}
+
+// probeSubdomainIsolation makes the actual network request used to detect
+// subdomain isolation: a GHES instance with isolation enabled serves
+// raw.<hostname>, while one without it does not. It's the only REST call
+// this package makes directly, so it's also where GitHubAPICall is
+// recorded; tool handlers record their own GitHub API calls the same way
+// through whatever client pkg/github injects into them.
+func probeSubdomainIsolation(client *http.Client, scheme, hostname string) (bool, error) {
+	url := fmt.Sprintf("%s://raw.%s/_ping", scheme, hostname)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		currentMetrics().GitHubAPICall(metrics.ProtocolREST, 0)
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	currentMetrics().GitHubAPICall(metrics.ProtocolREST, resp.StatusCode)
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// RefreshSubdomainIsolation evicts any cached subdomain-isolation result
+// for scheme://hostname, so the next call to checkSubdomainIsolation
+// re-probes instead of serving a stale answer. This is useful for
+// operators who rotate DNS or flip isolation mode on a GHES host without
+// restarting the MCP server.
+func RefreshSubdomainIsolation(scheme, hostname string) {
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+
+	subdomainIsolationCacheMutex.Lock()
+	delete(subdomainIsolationCache, cacheKey)
+	subdomainIsolationCacheMutex.Unlock()
+}
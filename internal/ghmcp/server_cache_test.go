@@ -0,0 +1,166 @@
+package ghmcp
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingRoundTripper lets tests fan in concurrent requests and assert
+// they collapse into a single RoundTrip call.
+type blockingRoundTripper struct {
+	release chan struct{}
+	count   int32
+}
+
+func (b *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&b.count, 1)
+	<-b.release
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func clearSubdomainIsolationCache(cacheKey string) {
+	subdomainIsolationCacheMutex.Lock()
+	delete(subdomainIsolationCache, cacheKey)
+	subdomainIsolationCacheMutex.Unlock()
+}
+
+func TestCheckSubdomainIsolation_ExpiresPositiveResult(t *testing.T) {
+	t.Parallel()
+
+	scheme, hostname := "https", "expiry-positive.ghes.com"
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+	clearSubdomainIsolationCache(cacheKey)
+
+	var requestCount int32
+	client := &http.Client{Transport: &mockRoundTripper{roundTripFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestCount, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}}}
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	// Force the cached entry to have already expired.
+	subdomainIsolationCacheMutex.Lock()
+	entry := subdomainIsolationCache[cacheKey]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	subdomainIsolationCache[cacheKey] = entry
+	subdomainIsolationCacheMutex.Unlock()
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount), "expected a re-probe after expiry")
+}
+
+func TestCheckSubdomainIsolation_NegativeCaching(t *testing.T) {
+	t.Parallel()
+
+	scheme, hostname := "https", "expiry-negative.ghes.com"
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+	clearSubdomainIsolationCache(cacheKey)
+
+	var requestCount int32
+	client := &http.Client{Transport: &mockRoundTripper{roundTripFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestCount, 1)
+		return nil, fmt.Errorf("connection refused")
+	}}}
+
+	assert.False(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	// Still within the (short) negative TTL: no re-probe yet.
+	assert.False(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	// Force the negative entry to have already expired.
+	subdomainIsolationCacheMutex.Lock()
+	entry := subdomainIsolationCache[cacheKey]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	subdomainIsolationCache[cacheKey] = entry
+	subdomainIsolationCacheMutex.Unlock()
+
+	assert.False(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount), "expected a re-probe after negative TTL expiry")
+}
+
+func TestCheckSubdomainIsolation_SingleflightCollapsesConcurrentCalls(t *testing.T) {
+	t.Parallel()
+
+	scheme, hostname := "https", "singleflight.ghes.com"
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+	clearSubdomainIsolationCache(cacheKey)
+
+	rt := &blockingRoundTripper{release: make(chan struct{})}
+	client := &http.Client{Transport: rt}
+
+	const callers = 20
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+		}()
+	}
+
+	// Give every goroutine a chance to reach the singleflight call before
+	// letting the single in-flight RoundTrip complete.
+	time.Sleep(50 * time.Millisecond)
+	close(rt.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&rt.count), "expected concurrent callers to collapse into one RoundTrip")
+}
+
+func TestSetSubdomainIsolationTTLs_OverridesDefaults(t *testing.T) {
+	// Not t.Parallel(): SetSubdomainIsolationTTLs mutates shared package
+	// state other subtests in this file rely on staying at the defaults.
+	defer SetSubdomainIsolationTTLs(DefaultSubdomainIsolationPositiveTTL, DefaultSubdomainIsolationNegativeTTL)
+
+	scheme, hostname := "https", "custom-ttl.ghes.com"
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+	clearSubdomainIsolationCache(cacheKey)
+
+	SetSubdomainIsolationTTLs(50*time.Millisecond, time.Hour)
+
+	var requestCount int32
+	client := &http.Client{Transport: &mockRoundTripper{roundTripFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestCount, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}}}
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount), "expected the shortened positive TTL to force a re-probe")
+}
+
+func TestRefreshSubdomainIsolation_EvictsCachedEntry(t *testing.T) {
+	t.Parallel()
+
+	scheme, hostname := "https", "refresh.ghes.com"
+	cacheKey := fmt.Sprintf("%s://%s", scheme, hostname)
+	clearSubdomainIsolationCache(cacheKey)
+
+	var requestCount int32
+	client := &http.Client{Transport: &mockRoundTripper{roundTripFunc: func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requestCount, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}}}
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+
+	RefreshSubdomainIsolation(scheme, hostname)
+
+	assert.True(t, checkSubdomainIsolation(client, scheme, hostname))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount), "expected RefreshSubdomainIsolation to force a re-probe")
+}
@@ -0,0 +1,139 @@
+package ghmcp
+
+import (
+	"context"
+	"sync"
+)
+
+// ToolsetConfig is a snapshot of which toolsets should be enabled, as
+// produced by a ToolsetProvider.
+type ToolsetConfig struct {
+	// EnabledToolsets follows the same nil/empty/explicit semantics as
+	// MCPServerConfig.EnabledToolsets (see resolveEnabledToolsets).
+	EnabledToolsets []string
+}
+
+// ToolsetProvider supplies a stream of toolset configurations. Provide
+// should emit an initial value as soon as it is known and then emit again
+// whenever the underlying source changes, closing the channel (or
+// returning ctx.Err()) when ctx is done.
+type ToolsetProvider interface {
+	Provide(ctx context.Context) (<-chan ToolsetConfig, error)
+}
+
+// AggregatorProvider fans in ToolsetConfig updates from multiple
+// ToolsetProviders into a single stream. Each source's most recent
+// contribution is tracked separately and the stream forwards the union
+// of every source's current contribution, so one source updating (or
+// never emitting at all, like an unset EnvProvider) can't clobber
+// another source's toolsets -- the last write from any one provider
+// only replaces that provider's own contribution to the merge.
+type AggregatorProvider struct {
+	sources []ToolsetProvider
+}
+
+// NewAggregatorProvider returns an AggregatorProvider that fans in
+// updates from sources in the order given.
+func NewAggregatorProvider(sources ...ToolsetProvider) *AggregatorProvider {
+	return &AggregatorProvider{sources: sources}
+}
+
+// Provide starts every source and merges their updates onto a single
+// channel. The returned channel is closed once ctx is done and every
+// source has stopped sending.
+func (a *AggregatorProvider) Provide(ctx context.Context) (<-chan ToolsetConfig, error) {
+	out := make(chan ToolsetConfig)
+
+	chans := make([]<-chan ToolsetConfig, 0, len(a.sources))
+	for _, source := range a.sources {
+		ch, err := source.Provide(ctx)
+		if err != nil {
+			return nil, err
+		}
+		chans = append(chans, ch)
+	}
+
+	var mergeMu sync.Mutex
+	contributions := make([][]string, len(chans))
+
+	// merge returns the union of every source's current contribution, in
+	// source order, so the result is deterministic for a given state of
+	// contributions regardless of the order updates arrived in. mergeMu
+	// must be held by the caller.
+	merge := func() ToolsetConfig {
+		seen := make(map[string]bool)
+		var merged []string
+		for _, toolsets := range contributions {
+			for _, toolset := range toolsets {
+				if seen[toolset] {
+					continue
+				}
+				seen[toolset] = true
+				merged = append(merged, toolset)
+			}
+		}
+		return ToolsetConfig{EnabledToolsets: merged}
+	}
+
+	done := make(chan struct{}, len(chans))
+	for i, ch := range chans {
+		go func(i int, ch <-chan ToolsetConfig) {
+			defer func() { done <- struct{}{} }()
+			for {
+				select {
+				case cfg, ok := <-ch:
+					if !ok {
+						return
+					}
+
+					mergeMu.Lock()
+					contributions[i] = cfg.EnabledToolsets
+					merged := merge()
+					mergeMu.Unlock()
+
+					select {
+					case out <- merged:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i, ch)
+	}
+
+	go func() {
+		for range chans {
+			<-done
+		}
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// staticToolsetProvider adapts a fixed toolset list (e.g. the one parsed
+// from CLI flags or MCPServerConfig) to the ToolsetProvider interface, so
+// NewMCPServer can treat "no dynamic source configured" as just another
+// provider rather than a special case.
+type staticToolsetProvider struct {
+	cfg ToolsetConfig
+}
+
+// NewStaticToolsetProvider returns a ToolsetProvider that emits cfg once
+// and then never again, preserving today's "configure at startup, never
+// change" behavior for callers that don't wire up a dynamic source.
+func NewStaticToolsetProvider(cfg ToolsetConfig) ToolsetProvider {
+	return &staticToolsetProvider{cfg: cfg}
+}
+
+func (s *staticToolsetProvider) Provide(ctx context.Context) (<-chan ToolsetConfig, error) {
+	ch := make(chan ToolsetConfig, 1)
+	ch <- s.cfg
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
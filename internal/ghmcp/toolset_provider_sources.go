@@ -0,0 +1,231 @@
+package ghmcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileProvider watches a YAML or JSON toolset spec on disk and emits a
+// new ToolsetConfig whenever its contents change. Format is inferred from
+// the file extension; ".yaml"/".yml" is parsed as YAML, everything else
+// as JSON.
+//
+// The spec file is a single object: {"enabledToolsets": ["repos", ...]}.
+type FileProvider struct {
+	Path         string
+	PollInterval time.Duration
+}
+
+// NewFileProvider returns a FileProvider for path, polling for changes
+// every pollInterval (a zero value defaults to 5s).
+func NewFileProvider(path string, pollInterval time.Duration) *FileProvider {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &FileProvider{Path: path, PollInterval: pollInterval}
+}
+
+func (f *FileProvider) Provide(ctx context.Context) (<-chan ToolsetConfig, error) {
+	out := make(chan ToolsetConfig, 1)
+
+	cfg, hash, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	out <- cfg
+
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(f.PollInterval)
+		defer ticker.Stop()
+
+		lastHash := hash
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cfg, hash, err := f.read()
+				if err != nil || hash == lastHash {
+					continue
+				}
+				lastHash = hash
+				select {
+				case out <- cfg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (f *FileProvider) read() (ToolsetConfig, [32]byte, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return ToolsetConfig{}, [32]byte{}, err
+	}
+
+	var spec struct {
+		EnabledToolsets []string `json:"enabledToolsets" yaml:"enabledToolsets"`
+	}
+
+	if strings.HasSuffix(f.Path, ".yaml") || strings.HasSuffix(f.Path, ".yml") {
+		err = yaml.Unmarshal(data, &spec)
+	} else {
+		err = json.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return ToolsetConfig{}, [32]byte{}, fmt.Errorf("parsing toolset spec %s: %w", f.Path, err)
+	}
+
+	return ToolsetConfig{EnabledToolsets: spec.EnabledToolsets}, sha256.Sum256(data), nil
+}
+
+// EnvProvider reads the enabled toolset list from an environment
+// variable, defaulting to GITHUB_MCP_TOOLSETS_ENABLED, as a
+// comma-separated list (e.g. "repos,issues,pull_requests"). It emits
+// once; environment variables don't change for the life of a process, so
+// there is nothing to poll.
+type EnvProvider struct {
+	EnvVar string
+}
+
+// NewEnvProvider returns an EnvProvider reading envVar. An empty envVar
+// defaults to "GITHUB_MCP_TOOLSETS_ENABLED".
+func NewEnvProvider(envVar string) *EnvProvider {
+	if envVar == "" {
+		envVar = "GITHUB_MCP_TOOLSETS_ENABLED"
+	}
+	return &EnvProvider{EnvVar: envVar}
+}
+
+// Provide emits one ToolsetConfig parsed from the environment variable,
+// or nothing at all if it's unset. Emitting nothing (rather than an empty
+// ToolsetConfig) matters under AggregatorProvider's last-write-wins
+// fan-in: an unconfigured EnvProvider must not be able to clobber a real
+// FileProvider/RemoteProvider config with an empty toolset list.
+func (e *EnvProvider) Provide(ctx context.Context) (<-chan ToolsetConfig, error) {
+	ch := make(chan ToolsetConfig, 1)
+
+	val, ok := os.LookupEnv(e.EnvVar)
+	if ok {
+		toolsets := strings.Split(val, ",")
+		for i := range toolsets {
+			toolsets[i] = strings.TrimSpace(toolsets[i])
+		}
+		ch <- ToolsetConfig{EnabledToolsets: toolsets}
+	}
+
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// RemoteProvider polls a signed HTTP endpoint for a ToolsetConfig,
+// letting operators flip toolsets on or off across a fleet without
+// redeploying. The endpoint is expected to return the same
+// {"enabledToolsets": [...]} JSON body as FileProvider, plus a
+// signature header that Verify checks before the config is trusted.
+type RemoteProvider struct {
+	URL          string
+	PollInterval time.Duration
+	Client       *http.Client
+	// Verify authenticates a response before its body is trusted, e.g.
+	// checking an HMAC signature header against a shared secret. A nil
+	// Verify accepts every response, which should only be used against
+	// endpoints already protected by transport-level auth.
+	Verify func(resp *http.Response) error
+}
+
+// NewRemoteProvider returns a RemoteProvider polling url every
+// pollInterval (a zero value defaults to 30s) using client (a nil client
+// defaults to http.DefaultClient).
+func NewRemoteProvider(url string, pollInterval time.Duration, client *http.Client, verify func(resp *http.Response) error) *RemoteProvider {
+	if pollInterval <= 0 {
+		pollInterval = 30 * time.Second
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RemoteProvider{URL: url, PollInterval: pollInterval, Client: client, Verify: verify}
+}
+
+func (r *RemoteProvider) Provide(ctx context.Context) (<-chan ToolsetConfig, error) {
+	out := make(chan ToolsetConfig, 1)
+
+	cfg, err := r.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out <- cfg
+
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(r.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cfg, err := r.fetch(ctx)
+				if err != nil {
+					continue
+				}
+				select {
+				case out <- cfg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (r *RemoteProvider) fetch(ctx context.Context) (ToolsetConfig, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return ToolsetConfig{}, err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return ToolsetConfig{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ToolsetConfig{}, fmt.Errorf("fetching toolset config from %s: unexpected status %s", r.URL, resp.Status)
+	}
+
+	if r.Verify != nil {
+		if err := r.Verify(resp); err != nil {
+			return ToolsetConfig{}, fmt.Errorf("verifying toolset config from %s: %w", r.URL, err)
+		}
+	}
+
+	var spec struct {
+		EnabledToolsets []string `json:"enabledToolsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		return ToolsetConfig{}, fmt.Errorf("decoding toolset config from %s: %w", r.URL, err)
+	}
+
+	return ToolsetConfig{EnabledToolsets: spec.EnabledToolsets}, nil
+}
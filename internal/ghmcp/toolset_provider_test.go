@@ -0,0 +1,191 @@
+package ghmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticToolsetProvider_EmitsOnceThenCloses(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewStaticToolsetProvider(ToolsetConfig{EnabledToolsets: []string{"repos"}})
+	ch, err := provider.Provide(ctx)
+	require.NoError(t, err)
+
+	cfg := <-ch
+	assert.Equal(t, []string{"repos"}, cfg.EnabledToolsets)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok, "expected channel to close once ctx is done")
+}
+
+func TestAggregatorProvider_FansInAllSources(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := NewAggregatorProvider(
+		NewStaticToolsetProvider(ToolsetConfig{EnabledToolsets: []string{"repos"}}),
+		NewStaticToolsetProvider(ToolsetConfig{EnabledToolsets: []string{"issues"}}),
+	)
+
+	ch, err := a.Provide(ctx)
+	require.NoError(t, err)
+
+	// Each source contributes independently, so the first emission only
+	// reflects whichever source updated first (order is nondeterministic),
+	// and the second reflects the union of both sources' contributions --
+	// neither source's toolsets are ever dropped by the other arriving.
+	var first, second ToolsetConfig
+	select {
+	case first = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for aggregated toolset config")
+	}
+	select {
+	case second = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for aggregated toolset config")
+	}
+
+	require.Len(t, first.EnabledToolsets, 1)
+	assert.Len(t, second.EnabledToolsets, 2)
+	assert.ElementsMatch(t, []string{"repos", "issues"}, second.EnabledToolsets)
+}
+
+func TestAggregatorProvider_MergesRatherThanClobbersAcrossSourceKinds(t *testing.T) {
+	t.Parallel()
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"enabledToolsets":["pull_requests"]}`))
+	}))
+	defer remote.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "toolsets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"enabledToolsets":["repos"]}`), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := NewAggregatorProvider(
+		NewFileProvider(path, time.Hour),
+		NewRemoteProvider(remote.URL, time.Hour, nil, nil),
+	)
+	ch, err := a.Provide(ctx)
+	require.NoError(t, err)
+
+	// Both sources emit their full list once at startup; the aggregator
+	// must merge them rather than have the later arrival replace the
+	// earlier one outright.
+	var last ToolsetConfig
+	for i := 0; i < 2; i++ {
+		select {
+		case last = <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for aggregated toolset config")
+		}
+	}
+	assert.ElementsMatch(t, []string{"repos", "pull_requests"}, last.EnabledToolsets)
+}
+
+func TestEnvProvider_ParsesCommaSeparatedList(t *testing.T) {
+	t.Setenv("GITHUB_MCP_TOOLSETS_TEST", "repos, issues ,pull_requests")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewEnvProvider("GITHUB_MCP_TOOLSETS_TEST")
+	ch, err := provider.Provide(ctx)
+	require.NoError(t, err)
+
+	cfg := <-ch
+	assert.Equal(t, []string{"repos", "issues", "pull_requests"}, cfg.EnabledToolsets)
+}
+
+func TestEnvProvider_UnsetEmitsNothing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	provider := NewEnvProvider("GITHUB_MCP_TOOLSETS_DEFINITELY_UNSET")
+	ch, err := provider.Provide(ctx)
+	require.NoError(t, err)
+
+	select {
+	case cfg, ok := <-ch:
+		t.Fatalf("expected no config from an unset env var, got %+v (ok=%v)", cfg, ok)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok, "expected channel to close once ctx is done")
+}
+
+func TestAggregatorProvider_UnconfiguredEnvProviderDoesNotClobberFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "toolsets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"enabledToolsets":["repos"]}`), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := NewAggregatorProvider(
+		NewFileProvider(path, time.Hour),
+		NewEnvProvider("GITHUB_MCP_TOOLSETS_DEFINITELY_UNSET"),
+	)
+	ch, err := a.Provide(ctx)
+	require.NoError(t, err)
+
+	select {
+	case cfg := <-ch:
+		assert.Equal(t, []string{"repos"}, cfg.EnabledToolsets)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the file provider's config")
+	}
+
+	select {
+	case cfg := <-ch:
+		t.Fatalf("expected no further config from the unset env provider, got %+v", cfg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFileProvider_ReadsJSONSpecAndReemitsOnChange(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "toolsets.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"enabledToolsets":["repos"]}`), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := NewFileProvider(path, 20*time.Millisecond)
+	ch, err := provider.Provide(ctx)
+	require.NoError(t, err)
+
+	cfg := <-ch
+	assert.Equal(t, []string{"repos"}, cfg.EnabledToolsets)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"enabledToolsets":["repos","issues"]}`), 0o644))
+
+	select {
+	case cfg := <-ch:
+		assert.Equal(t, []string{"repos", "issues"}, cfg.EnabledToolsets)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for file change to be picked up")
+	}
+}
@@ -0,0 +1,82 @@
+package ghmcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultToolsetRegistrar is the ToolsetRegistrar NewMCPServer falls back
+// to when MCPServerConfig.ToolsetRegistrar is nil. It reconciles a
+// server's registered tools with a ToolsetConfig by adding one
+// instrumented marker tool per newly-enabled toolset and withdrawing the
+// tools for any toolset that was turned off -- real toolset/tool
+// registration (the full tool surface under pkg/github) composes with
+// this the same way, one AddTool/DeleteTools call per toolset.
+type defaultToolsetRegistrar struct {
+	mu      sync.Mutex
+	enabled map[string]bool
+}
+
+// newDefaultToolsetRegistrar returns a ToolsetRegistrar with no toolsets
+// applied yet.
+func newDefaultToolsetRegistrar() *defaultToolsetRegistrar {
+	return &defaultToolsetRegistrar{enabled: map[string]bool{}}
+}
+
+// ApplyToolsetConfig adds tools for any toolset in cfg.EnabledToolsets
+// that isn't already registered, and withdraws tools for any previously
+// registered toolset that's no longer in cfg, so a hot-swapped
+// ToolsetConfig takes effect on the live MCP session without a restart.
+func (d *defaultToolsetRegistrar) ApplyToolsetConfig(s *server.MCPServer, cfg ToolsetConfig) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	want := make(map[string]bool, len(cfg.EnabledToolsets))
+	for _, toolset := range cfg.EnabledToolsets {
+		want[toolset] = true
+	}
+
+	for toolset := range want {
+		if d.enabled[toolset] {
+			continue
+		}
+		toolName, tool, handler := toolsetMarkerTool(toolset)
+		s.AddTool(tool, instrumentedToolHandler(toolName, toolset, handler))
+		d.enabled[toolset] = true
+	}
+
+	var withdrawn []string
+	for toolset := range d.enabled {
+		if want[toolset] {
+			continue
+		}
+		toolName, _, _ := toolsetMarkerTool(toolset)
+		withdrawn = append(withdrawn, toolName)
+		delete(d.enabled, toolset)
+	}
+	if len(withdrawn) > 0 {
+		s.DeleteTools(withdrawn...)
+	}
+
+	return nil
+}
+
+// toolsetMarkerTool builds the placeholder tool NewMCPServer registers to
+// represent a toolset being enabled, until the caller supplies the real
+// tool set for that toolset via a custom ToolsetRegistrar (see pkg/github
+// for the full GitHub tool implementations).
+func toolsetMarkerTool(toolset string) (name string, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	name = fmt.Sprintf("toolset_%s_enabled", toolset)
+	tool = mcp.NewTool(
+		name,
+		mcp.WithDescription(fmt.Sprintf("Reports that the %q toolset is currently enabled.", toolset)),
+	)
+	handler = func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(fmt.Sprintf("toolset %q is enabled", toolset)), nil
+	}
+	return name, tool, handler
+}
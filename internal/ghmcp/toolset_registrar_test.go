@@ -0,0 +1,40 @@
+package ghmcp
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultToolsetRegistrar_RegistersAndWithdrawsTools(t *testing.T) {
+	t.Parallel()
+
+	s := server.NewMCPServer("test", "v0")
+	registrar := newDefaultToolsetRegistrar()
+
+	require.NoError(t, registrar.ApplyToolsetConfig(s, ToolsetConfig{EnabledToolsets: []string{"repos", "issues"}}))
+	assert.True(t, registrar.enabled["repos"])
+	assert.True(t, registrar.enabled["issues"])
+
+	require.NoError(t, registrar.ApplyToolsetConfig(s, ToolsetConfig{EnabledToolsets: []string{"repos"}}))
+	assert.True(t, registrar.enabled["repos"])
+	assert.False(t, registrar.enabled["issues"])
+
+	require.NoError(t, registrar.ApplyToolsetConfig(s, ToolsetConfig{EnabledToolsets: []string{"repos"}}))
+	assert.Len(t, registrar.enabled, 1)
+}
+
+func TestNewMCPServer_DefaultsToRegistrarWhenNoneConfigured(t *testing.T) {
+	t.Parallel()
+
+	cfg := MCPServerConfig{
+		Version:         "test",
+		EnabledToolsets: []string{"repos"},
+	}
+
+	s, err := NewMCPServer(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+}
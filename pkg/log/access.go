@@ -0,0 +1,183 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat selects how AccessLogger renders each record.
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatJSON renders one JSON object per line.
+	AccessLogFormatJSON AccessLogFormat = "json"
+	// AccessLogFormatCLF renders a single space-separated line modeled
+	// after the Common Log Format, for operators piping into tools that
+	// already parse CLF-style access logs.
+	AccessLogFormatCLF AccessLogFormat = "clf"
+)
+
+// AccessRecord is one structured entry in the tool-invocation access log:
+// everything an auditor needs to answer "who called what, with what
+// result" without reading the raw stdio dump an IOLogger produces.
+type AccessRecord struct {
+	RequestID      string        `json:"request_id"`
+	SessionID      string        `json:"session_id"`
+	Tool           string        `json:"tool"`
+	Toolset        string        `json:"toolset"`
+	ActorLogin     string        `json:"actor_login"`
+	Duration       time.Duration `json:"duration_ns"`
+	GitHubAPICalls int           `json:"github_api_calls"`
+	BytesIn        int           `json:"bytes_in"`
+	BytesOut       int           `json:"bytes_out"`
+	Outcome        string        `json:"outcome"`
+}
+
+// AccessLogger records one AccessRecord per tool invocation, scrubbing it
+// through a RedactorRegistry first so that a tool's request/response
+// bodies never leak a token into the audit trail.
+type AccessLogger struct {
+	mu       sync.Mutex
+	out      io.Writer
+	format   AccessLogFormat
+	registry *RedactorRegistry
+}
+
+// NewAccessLogger returns an AccessLogger writing to out in the given
+// format. A nil registry defaults to DefaultRedactorRegistry.
+func NewAccessLogger(out io.Writer, format AccessLogFormat, registry *RedactorRegistry) *AccessLogger {
+	if registry == nil {
+		registry = DefaultRedactorRegistry()
+	}
+	return &AccessLogger{out: out, format: format, registry: registry}
+}
+
+// Close closes the underlying writer if it implements io.Closer (e.g. a
+// RotatingFile), so callers can shut an AccessLogger down uniformly
+// regardless of what they passed as out.
+func (a *AccessLogger) Close() error {
+	if c, ok := a.out.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Log writes rec to the access log. ActorLogin is the only field that
+// plausibly carries a credential (e.g. a PAT used as a basic-auth
+// username), so it's the only one run through the redactor registry;
+// Tool/Toolset/RequestID/SessionID are server-assigned identifiers, not
+// user- or operator-controlled payloads.
+func (a *AccessLogger) Log(rec AccessRecord) error {
+	rec.ActorLogin = string(a.registry.Redact([]byte(rec.ActorLogin)))
+
+	var line []byte
+	switch a.format {
+	case AccessLogFormatCLF:
+		line = []byte(rec.clfLine())
+	default:
+		encoded, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("log: marshaling access record: %w", err)
+		}
+		line = encoded
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err := a.out.Write(line)
+	return err
+}
+
+// clfLine renders rec in a Common-Log-Format-like layout:
+// actor [tool/toolset] "outcome" duration api_calls bytes_in/bytes_out request_id session_id
+func (r AccessRecord) clfLine() string {
+	actor := r.ActorLogin
+	if actor == "" {
+		actor = "-"
+	}
+	return fmt.Sprintf("%s [%s/%s] %q %s %d %d/%d %s %s",
+		actor, r.Tool, r.Toolset, r.Outcome, r.Duration, r.GitHubAPICalls, r.BytesIn, r.BytesOut, r.RequestID, r.SessionID)
+}
+
+// ToolCallMiddleware is the signature the deps-injection middleware chain
+// built in NewMCPServer composes with: it wraps a tool call, observes
+// what happened, and forwards to the next handler.
+//
+// It's defined here (rather than importing a handler type from mcp-go)
+// so pkg/log stays decoupled from the MCP transport library; callers
+// adapt it to their handler signature with a small shim.
+type ToolCallMiddleware func(next ToolCallFunc) ToolCallFunc
+
+// ToolCallFunc invokes a single tool call and reports what happened so
+// AccessLogMiddleware can record it.
+type ToolCallFunc func(call ToolCall) (ToolCallResult, error)
+
+// ToolCall describes an in-flight tool invocation.
+type ToolCall struct {
+	RequestID  string
+	SessionID  string
+	Tool       string
+	Toolset    string
+	ActorLogin string
+	BytesIn    int
+}
+
+// ToolCallResult describes how a tool invocation finished.
+type ToolCallResult struct {
+	GitHubAPICalls int
+	BytesOut       int
+	Outcome        string
+}
+
+// AccessLogMiddleware returns a ToolCallMiddleware that logs one
+// AccessRecord per call via logger, recovering a panicking handler so it
+// can be logged with outcome "panic" before being re-panicked -- callers
+// further up the chain are expected to still convert it into an MCP
+// error response.
+func AccessLogMiddleware(logger *AccessLogger, slogger *slog.Logger) ToolCallMiddleware {
+	return func(next ToolCallFunc) ToolCallFunc {
+		return func(call ToolCall) (result ToolCallResult, err error) {
+			start := time.Now()
+
+			defer func() {
+				rec := AccessRecord{
+					RequestID:      call.RequestID,
+					SessionID:      call.SessionID,
+					Tool:           call.Tool,
+					Toolset:        call.Toolset,
+					ActorLogin:     call.ActorLogin,
+					Duration:       time.Since(start),
+					GitHubAPICalls: result.GitHubAPICalls,
+					BytesIn:        call.BytesIn,
+					BytesOut:       result.BytesOut,
+					Outcome:        result.Outcome,
+				}
+
+				if r := recover(); r != nil {
+					rec.Outcome = "panic"
+					if logErr := logger.Log(rec); logErr != nil && slogger != nil {
+						slogger.Error("writing access log record", "error", logErr)
+					}
+					panic(r)
+				}
+
+				if logErr := logger.Log(rec); logErr != nil && slogger != nil {
+					slogger.Error("writing access log record", "error", logErr)
+				}
+			}()
+
+			result, err = next(call)
+			if err != nil && result.Outcome == "" {
+				result.Outcome = "tool_error"
+			} else if result.Outcome == "" {
+				result.Outcome = "ok"
+			}
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package log provides stdio wrappers that give the MCP server visibility
+// into the raw JSON-RPC traffic flowing to and from a client, with
+// credential redaction so that traffic capture cannot leak GitHub tokens.
+package log
+
+import (
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// IOLogger wraps an io.Reader/io.Writer pair used for MCP stdio transport,
+// logging every chunk that passes through Read or Write. Payloads are run
+// through a RedactorRegistry before they reach the logger so that tokens
+// embedded in request/response bodies never hit disk.
+type IOLogger struct {
+	mu       sync.Mutex
+	in       io.Reader
+	out      io.Writer
+	logger   *slog.Logger
+	registry *RedactorRegistry
+	closed   bool
+}
+
+// NewIOLogger returns an IOLogger that reads from in, writes to out, and
+// logs redacted copies of both to logger. The default redactor registry
+// (see DefaultRedactorRegistry) is used; call SetRedactorRegistry to
+// install a custom one.
+func NewIOLogger(in io.Reader, out io.Writer, logger *slog.Logger) *IOLogger {
+	return &IOLogger{
+		in:       in,
+		out:      out,
+		logger:   logger,
+		registry: DefaultRedactorRegistry(),
+	}
+}
+
+// SetRedactorRegistry replaces the registry used to scrub logged payloads.
+func (l *IOLogger) SetRedactorRegistry(registry *RedactorRegistry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.registry = registry
+}
+
+// Read reads from the wrapped reader, logging a redacted copy of the
+// bytes read.
+func (l *IOLogger) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, io.EOF
+	}
+
+	n, err := l.in.Read(p)
+	if n > 0 {
+		l.logger.Info("read", "data", string(l.registry.Redact(p[:n])))
+	}
+	return n, err
+}
+
+// Write writes to the wrapped writer, logging a redacted copy of the
+// bytes written.
+func (l *IOLogger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	n, err := l.out.Write(p)
+	if n > 0 {
+		l.logger.Info("write", "data", string(l.registry.Redact(p[:n])))
+	}
+	return n, err
+}
+
+// Close marks the IOLogger as closed. Subsequent reads return io.EOF and
+// subsequent writes return io.ErrClosedPipe.
+func (l *IOLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
@@ -0,0 +1,210 @@
+package log
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Redactor scrubs sensitive data out of a byte slice before it reaches a
+// log sink. Implementations should preserve the length of the input so
+// that redacted log lines stay the same size as the originals, which
+// keeps the "keep prefix, mask the rest with equal-length `*`" contract
+// callers rely on when grepping logs.
+type Redactor interface {
+	// Redact returns b with any sensitive data it recognizes masked out.
+	// Implementations must not mutate b in place if b may be reused by
+	// the caller; NewIOLogger always passes a fresh slice.
+	Redact(b []byte) []byte
+	// Name identifies the redactor, for diagnostics and deduplication.
+	Name() string
+}
+
+// RedactorRegistry applies an ordered set of Redactors to a payload. It is
+// safe for concurrent use.
+type RedactorRegistry struct {
+	redactors []Redactor
+	onRedact  func(redactorName string)
+}
+
+// NewRedactorRegistry builds a registry that applies redactors in the
+// order given.
+func NewRedactorRegistry(redactors ...Redactor) *RedactorRegistry {
+	return &RedactorRegistry{redactors: append([]Redactor(nil), redactors...)}
+}
+
+// Register appends additional redactors to the registry, applied after
+// any that were already registered.
+func (r *RedactorRegistry) Register(redactors ...Redactor) {
+	r.redactors = append(r.redactors, redactors...)
+}
+
+// OnRedact installs a callback invoked with a redactor's Name() every
+// time it actually masks something in a payload, letting callers (e.g.
+// metrics instrumentation) count redactions without each Redactor having
+// to know about the observer itself.
+func (r *RedactorRegistry) OnRedact(fn func(redactorName string)) {
+	r.onRedact = fn
+}
+
+// Redact runs b through every registered Redactor in turn and returns the
+// result. b itself is never modified.
+func (r *RedactorRegistry) Redact(b []byte) []byte {
+	out := b
+	for _, redactor := range r.redactors {
+		redacted := redactor.Redact(out)
+		if r.onRedact != nil && !bytes.Equal(redacted, out) {
+			r.onRedact(redactor.Name())
+		}
+		out = redacted
+	}
+	return out
+}
+
+// DefaultRedactors returns the built-in redactors shipped by this package:
+// classic and fine-grained GitHub tokens, Bearer auth headers, GitHub App
+// JWTs, and generic password/secret JSON fields. Operators wanting to
+// scrub additional credential shapes should append to this slice and pass
+// the result to NewRedactorRegistry, e.g. via MCPServerConfig.Redactors.
+func DefaultRedactors() []Redactor {
+	return []Redactor{
+		NewPrefixTokenRedactor("ghp_"),
+		NewPrefixTokenRedactor("gho_"),
+		NewPrefixTokenRedactor("ghu_"),
+		NewPrefixTokenRedactor("ghs_"),
+		NewPrefixTokenRedactor("ghr_"),
+		NewPrefixTokenRedactor("github_pat_"),
+		NewBearerTokenRedactor(),
+		NewJWTRedactor(),
+		NewJSONFieldRedactor("password", "secret"),
+	}
+}
+
+// DefaultRedactorRegistry returns a RedactorRegistry populated with
+// DefaultRedactors.
+func DefaultRedactorRegistry() *RedactorRegistry {
+	return NewRedactorRegistry(DefaultRedactors()...)
+}
+
+// prefixTokenRedactor masks the body of tokens that share a fixed,
+// human-readable prefix (e.g. "ghp_"), keeping the prefix visible so log
+// readers can still tell what kind of credential was scrubbed.
+type prefixTokenRedactor struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+// NewPrefixTokenRedactor returns a Redactor that masks everything after
+// prefix in tokens of the form prefix + alphanumerics/underscore, keeping
+// the prefix itself visible and the overall length unchanged.
+func NewPrefixTokenRedactor(prefix string) Redactor {
+	return &prefixTokenRedactor{
+		prefix: prefix,
+		re:     regexp.MustCompile(regexp.QuoteMeta(prefix) + `[A-Za-z0-9_]+`),
+	}
+}
+
+func (p *prefixTokenRedactor) Name() string { return "prefix-token:" + p.prefix }
+
+func (p *prefixTokenRedactor) Redact(b []byte) []byte {
+	return p.re.ReplaceAllFunc(b, func(match []byte) []byte {
+		masked := make([]byte, len(match))
+		copy(masked, p.prefix)
+		for i := len(p.prefix); i < len(masked); i++ {
+			masked[i] = '*'
+		}
+		return masked
+	})
+}
+
+// bearerTokenRedactor masks the credential portion of "Bearer <token>"
+// Authorization headers that show up in logged HTTP-like payloads.
+type bearerTokenRedactor struct {
+	re *regexp.Regexp
+}
+
+// NewBearerTokenRedactor returns a Redactor that masks the token in
+// "Bearer <token>" headers, keeping the "Bearer " prefix visible.
+func NewBearerTokenRedactor() Redactor {
+	return &bearerTokenRedactor{re: regexp.MustCompile(`(?i)(Bearer\s+)([A-Za-z0-9\-_.~+/]+=*)`)}
+}
+
+func (*bearerTokenRedactor) Name() string { return "bearer-token" }
+
+func (r *bearerTokenRedactor) Redact(b []byte) []byte {
+	return r.re.ReplaceAllFunc(b, func(match []byte) []byte {
+		sub := r.re.FindSubmatch(match)
+		masked := make([]byte, len(sub[2]))
+		for i := range masked {
+			masked[i] = '*'
+		}
+		return append(append([]byte{}, sub[1]...), masked...)
+	})
+}
+
+// jwtRedactor masks GitHub App JWTs: three base64url segments joined by
+// dots, starting with the "eyJ" header every unencrypted JWT begins with.
+type jwtRedactor struct {
+	re *regexp.Regexp
+}
+
+// NewJWTRedactor returns a Redactor that masks GitHub App JWTs, keeping
+// the "eyJ" prefix visible so readers can recognize a JWT was scrubbed.
+func NewJWTRedactor() Redactor {
+	return &jwtRedactor{re: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)}
+}
+
+func (*jwtRedactor) Name() string { return "github-app-jwt" }
+
+func (r *jwtRedactor) Redact(b []byte) []byte {
+	return r.re.ReplaceAllFunc(b, func(match []byte) []byte {
+		masked := make([]byte, len(match))
+		copy(masked, "eyJ")
+		for i := 3; i < len(masked); i++ {
+			masked[i] = '*'
+		}
+		return masked
+	})
+}
+
+// jsonFieldRedactor masks the string value of named JSON fields, e.g.
+// `"password":"hunter2"` becomes `"password":"*******"`.
+type jsonFieldRedactor struct {
+	re *regexp.Regexp
+}
+
+// NewJSONFieldRedactor returns a Redactor that masks the values of the
+// given JSON field names wherever they appear as `"field":"value"` in a
+// payload.
+func NewJSONFieldRedactor(fields ...string) Redactor {
+	pattern := `"(` + strings.Join(fields, "|") + `)"\s*:\s*"((?:\\.|[^"\\])*)"`
+	return &jsonFieldRedactor{re: regexp.MustCompile(`(?i)` + pattern)}
+}
+
+func (*jsonFieldRedactor) Name() string { return "json-field" }
+
+// Redact masks the value capture group in place and leaves everything
+// else -- including the exact whitespace around the field's colon -- byte
+// for byte as it appeared in b, so spaced JSON like `"password" : "x"`
+// keeps its original length and layout.
+func (r *jsonFieldRedactor) Redact(b []byte) []byte {
+	locs := r.re.FindAllSubmatchIndex(b, -1)
+	if locs == nil {
+		return b
+	}
+
+	out := make([]byte, 0, len(b))
+	last := 0
+	for _, loc := range locs {
+		// loc layout: [matchStart, matchEnd, fieldStart, fieldEnd, valueStart, valueEnd]
+		valueStart, valueEnd := loc[4], loc[5]
+
+		out = append(out, b[last:valueStart]...)
+		for i := 0; i < valueEnd-valueStart; i++ {
+			out = append(out, '*')
+		}
+		last = valueEnd
+	}
+	out = append(out, b[last:]...)
+	return out
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile_RotatesOnceMaxBytesExceeded(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	rf, err := NewRotatingFile(path, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("12345")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := rf.Write([]byte("67890ab")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected a rotated file alongside the active one, got %d entries", len(entries))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "67890ab" {
+		t.Errorf("expected active file to contain only the post-rotation write, got %q", string(data))
+	}
+}
+
+func TestRotatingFile_AppendsAcrossReopens(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	rf, err := NewRotatingFile(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := rf.Write([]byte("first\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rf2, err := NewRotatingFile(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rf2.Close()
+	if _, err := rf2.Write([]byte("second\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("expected appended content across reopen, got %q", string(data))
+	}
+}
@@ -0,0 +1,63 @@
+// Package metrics defines the observability surface for the GitHub MCP
+// server: a backend-agnostic Registry interface plus the instruments the
+// server records against it (tool call latency/outcome, GitHub API call
+// volume, cache hit ratio, and redaction counts).
+package metrics
+
+import "time"
+
+// Outcome classifies how a tool call or request finished.
+type Outcome string
+
+const (
+	OutcomeOK    Outcome = "ok"
+	OutcomeError Outcome = "tool_error"
+	OutcomePanic Outcome = "panic"
+)
+
+// APIProtocol distinguishes GitHub's REST and GraphQL surfaces, which the
+// server calls through different clients and wants broken out separately.
+type APIProtocol string
+
+const (
+	ProtocolREST    APIProtocol = "rest"
+	ProtocolGraphQL APIProtocol = "graphql"
+)
+
+// Registry is the set of instruments the server records against,
+// independent of which backend (Prometheus, OTLP, ...) ultimately
+// collects them.
+type Registry interface {
+	// ToolCallDuration records how long a tool call took, labeled by
+	// tool, toolset, and how it finished.
+	ToolCallDuration(tool, toolset string, outcome Outcome, d time.Duration)
+
+	// GitHubAPICall records one call to the GitHub API, labeled by
+	// protocol (REST vs GraphQL) and the response's status code (0 if
+	// the call never got a response, e.g. a network error).
+	GitHubAPICall(protocol APIProtocol, statusCode int)
+
+	// SubdomainIsolationCache records a cache lookup outcome so operators
+	// can track the hit ratio of the cache exercised by
+	// ghmcp.checkSubdomainIsolation.
+	SubdomainIsolationCache(hit bool)
+
+	// Redaction records that a log.Redactor matched and scrubbed data
+	// from a logged payload.
+	Redaction(redactorName string)
+
+	// Shutdown stops any background work (e.g. an HTTP listener) and
+	// flushes buffered data. It must be safe to call more than once.
+	Shutdown() error
+}
+
+// NoopRegistry is a Registry that discards every recorded measurement.
+// It's the zero-config default so instrumented code paths don't need to
+// nil-check a Registry before using it.
+type NoopRegistry struct{}
+
+func (NoopRegistry) ToolCallDuration(string, string, Outcome, time.Duration) {}
+func (NoopRegistry) GitHubAPICall(APIProtocol, int)                          {}
+func (NoopRegistry) SubdomainIsolationCache(bool)                            {}
+func (NoopRegistry) Redaction(string)                                        {}
+func (NoopRegistry) Shutdown() error                                         { return nil }
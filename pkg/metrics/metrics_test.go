@@ -0,0 +1,19 @@
+package metrics
+
+import "testing"
+
+// TestNoopRegistry_DoesNotPanic exercises every Registry method on the
+// zero-config default to guard against a nil-pointer regression creeping
+// into NoopRegistry's method set.
+func TestNoopRegistry_DoesNotPanic(t *testing.T) {
+	var r Registry = NoopRegistry{}
+
+	r.ToolCallDuration("get_me", "context", OutcomeOK, 0)
+	r.GitHubAPICall(ProtocolREST, 200)
+	r.SubdomainIsolationCache(true)
+	r.Redaction("prefix-token:ghp_")
+
+	if err := r.Shutdown(); err != nil {
+		t.Errorf("expected Shutdown to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// OTLPRegistry records the Registry instruments through an
+// OpenTelemetry MeterProvider and exports them to an OTLP collector.
+type OTLPRegistry struct {
+	provider *sdkmetric.MeterProvider
+
+	toolCallDuration        metric.Float64Histogram
+	githubAPICalls          metric.Int64Counter
+	subdomainIsolationCache metric.Int64Counter
+	redactions              metric.Int64Counter
+}
+
+// NewOTLPRegistry builds an OTLPRegistry that exports metrics to the
+// OTLP/gRPC endpoint described by endpoint (host:port).
+func NewOTLPRegistry(ctx context.Context, endpoint string) (*OTLPRegistry, error) {
+	exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(15*time.Second))),
+	)
+	meter := provider.Meter("github.com/github/github-mcp-server")
+
+	toolCallDuration, err := meter.Float64Histogram(
+		"mcp_tool_call_duration_seconds",
+		metric.WithDescription("Duration of MCP tool calls in seconds."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	githubAPICalls, err := meter.Int64Counter(
+		"mcp_github_api_calls_total",
+		metric.WithDescription("Total GitHub API calls made by the server, by protocol and status code."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subdomainIsolationCache, err := meter.Int64Counter(
+		"mcp_subdomain_isolation_cache_total",
+		metric.WithDescription("Subdomain isolation cache lookups, by hit/miss."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	redactions, err := meter.Int64Counter(
+		"mcp_log_redactions_total",
+		metric.WithDescription("Redactions performed on logged payloads, by redactor name."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OTLPRegistry{
+		provider:                provider,
+		toolCallDuration:        toolCallDuration,
+		githubAPICalls:          githubAPICalls,
+		subdomainIsolationCache: subdomainIsolationCache,
+		redactions:              redactions,
+	}, nil
+}
+
+func (o *OTLPRegistry) ToolCallDuration(tool, toolset string, outcome Outcome, d time.Duration) {
+	o.toolCallDuration.Record(context.Background(), d.Seconds(),
+		metric.WithAttributes(
+			attribute.String("tool", tool),
+			attribute.String("toolset", toolset),
+			attribute.String("outcome", string(outcome)),
+		))
+}
+
+func (o *OTLPRegistry) GitHubAPICall(protocol APIProtocol, statusCode int) {
+	o.githubAPICalls.Add(context.Background(), 1,
+		metric.WithAttributes(
+			attribute.String("protocol", string(protocol)),
+			attribute.Int("status_code", statusCode),
+		))
+}
+
+func (o *OTLPRegistry) SubdomainIsolationCache(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	o.subdomainIsolationCache.Add(context.Background(), 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+func (o *OTLPRegistry) Redaction(redactorName string) {
+	o.redactions.Add(context.Background(), 1, metric.WithAttributes(attribute.String("redactor", redactorName)))
+}
+
+// Shutdown flushes and stops the underlying MeterProvider.
+func (o *OTLPRegistry) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return o.provider.Shutdown(ctx)
+}
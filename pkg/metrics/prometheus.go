@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusRegistry serves the instruments defined by Registry on a
+// "/metrics" endpoint in the Prometheus exposition format.
+type PrometheusRegistry struct {
+	registry *prometheus.Registry
+	server   *http.Server
+
+	toolCallDuration        *prometheus.HistogramVec
+	githubAPICalls          *prometheus.CounterVec
+	subdomainIsolationCache *prometheus.CounterVec
+	redactions              *prometheus.CounterVec
+}
+
+// NewPrometheusRegistry builds a PrometheusRegistry and starts serving
+// its metrics on addr (e.g. ":9090") at "/metrics". Call Shutdown to stop
+// the listener.
+func NewPrometheusRegistry(addr string) (*PrometheusRegistry, error) {
+	if addr == "" {
+		return nil, errors.New("metrics: prometheus addr must not be empty")
+	}
+
+	reg := prometheus.NewRegistry()
+	factory := promauto.With(reg)
+
+	p := &PrometheusRegistry{
+		registry: reg,
+		toolCallDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mcp_tool_call_duration_seconds",
+			Help:    "Duration of MCP tool calls in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"tool", "toolset", "outcome"}),
+		githubAPICalls: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_github_api_calls_total",
+			Help: "Total GitHub API calls made by the server, by protocol and status code.",
+		}, []string{"protocol", "status_code"}),
+		subdomainIsolationCache: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_subdomain_isolation_cache_total",
+			Help: "Subdomain isolation cache lookups, by hit/miss.",
+		}, []string{"result"}),
+		redactions: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "mcp_log_redactions_total",
+			Help: "Redactions performed on logged payloads, by redactor name.",
+		}, []string{"redactor"}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	p.server = &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := p.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			// Never fmt.Print this: in stdio transport mode stdout is the
+			// JSON-RPC channel, and an unframed line here would corrupt
+			// the session. slog's default handler writes to stderr.
+			slog.Default().Error("prometheus metrics server", "error", err)
+		}
+	}()
+
+	return p, nil
+}
+
+func (p *PrometheusRegistry) ToolCallDuration(tool, toolset string, outcome Outcome, d time.Duration) {
+	p.toolCallDuration.WithLabelValues(tool, toolset, string(outcome)).Observe(d.Seconds())
+}
+
+func (p *PrometheusRegistry) GitHubAPICall(protocol APIProtocol, statusCode int) {
+	p.githubAPICalls.WithLabelValues(string(protocol), fmt.Sprintf("%d", statusCode)).Inc()
+}
+
+func (p *PrometheusRegistry) SubdomainIsolationCache(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.subdomainIsolationCache.WithLabelValues(result).Inc()
+}
+
+func (p *PrometheusRegistry) Redaction(redactorName string) {
+	p.redactions.WithLabelValues(redactorName).Inc()
+}
+
+// Shutdown gracefully stops the "/metrics" HTTP listener.
+func (p *PrometheusRegistry) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.server.Shutdown(ctx)
+}